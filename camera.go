@@ -0,0 +1,33 @@
+package main
+
+// Camera describes a perspective viewpoint: where it sits, what it is
+// looking at, and the frustum used to project the scene onto the window.
+type Camera struct {
+	Position Vector
+	Target   Vector
+	Up       Vector
+	Fov      float64 // vertical field of view, in radians
+	Near     float64
+	Far      float64
+	Aspect   float64
+}
+
+func NewCamera(position, target, up Vector, fov, near, far, aspect float64) *Camera {
+	return &Camera{
+		Position: position,
+		Target:   target,
+		Up:       up,
+		Fov:      fov,
+		Near:     near,
+		Far:      far,
+		Aspect:   aspect,
+	}
+}
+
+func (c *Camera) View() Mat4 {
+	return LookAt(c.Position, c.Target, c.Up)
+}
+
+func (c *Camera) Projection() Mat4 {
+	return Perspective(c.Fov, c.Aspect, c.Near, c.Far)
+}
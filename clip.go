@@ -0,0 +1,97 @@
+package main
+
+// CullMode controls which triangle windings Space.Draw discards before
+// rasterizing, based on the triangle's camera-space facing direction.
+type CullMode int
+
+const (
+	CullNone CullMode = iota
+	CullBack
+	CullFront
+)
+
+func triangleNormal(v0, v1, v2 Point) Vector {
+	e1 := Vector{X: v1.X - v0.X, Y: v1.Y - v0.Y, Z: v1.Z - v0.Z}
+	e2 := Vector{X: v2.X - v0.X, Y: v2.Y - v0.Y, Z: v2.Z - v0.Z}
+	return cross(e1, e2)
+}
+
+// culled reports whether a camera-space triangle with normal n should be
+// discarded for this CullMode. The camera looks down -Z in camera space,
+// so (0,0,-1) stands in for viewDir.
+func (mode CullMode) culled(n Vector) bool {
+	facing := dot(n, Vector{X: 0, Y: 0, Z: -1})
+	switch mode {
+	case CullBack:
+		return facing >= 0
+	case CullFront:
+		return facing <= 0
+	default:
+		return false
+	}
+}
+
+// clipNear clips a camera-space triangle against the near plane z = -near
+// with Sutherland-Hodgman, returning zero, one or two triangles depending
+// on how many of the three vertices are behind the plane. Color is
+// interpolated alongside position at any new intersection vertex.
+func clipNear(tri [3]ShadedVertex, near float64) [][3]ShadedVertex {
+	var inCount int
+	var in [3]bool
+	for i, v := range tri {
+		in[i] = v.Point.Z <= -near
+		if in[i] {
+			inCount++
+		}
+	}
+
+	switch inCount {
+	case 0:
+		return nil
+	case 3:
+		return [][3]ShadedVertex{tri}
+	default:
+		return clipMixed(tri, in, near)
+	}
+}
+
+// clipMixed handles the 1-in/2-out and 2-in/1-out cases, interpolating
+// the intersection with the near plane at t = (-near - z_a) / (z_b - z_a).
+func clipMixed(tri [3]ShadedVertex, in [3]bool, near float64) [][3]ShadedVertex {
+	var insiders, outsiders []ShadedVertex
+	for i, v := range tri {
+		if in[i] {
+			insiders = append(insiders, v)
+		} else {
+			outsiders = append(outsiders, v)
+		}
+	}
+
+	if len(insiders) == 1 {
+		a := insiders[0]
+		b := intersectNearPlane(a, outsiders[0], near)
+		c := intersectNearPlane(a, outsiders[1], near)
+		return [][3]ShadedVertex{{a, b, c}}
+	}
+
+	a, b := insiders[0], insiders[1]
+	o := outsiders[0]
+	ca := intersectNearPlane(a, o, near)
+	cb := intersectNearPlane(b, o, near)
+	return [][3]ShadedVertex{
+		{a, b, ca},
+		{b, cb, ca},
+	}
+}
+
+func intersectNearPlane(a, b ShadedVertex, near float64) ShadedVertex {
+	t := (-near - a.Point.Z) / (b.Point.Z - a.Point.Z)
+	return ShadedVertex{
+		Point: Point{
+			X: a.Point.X + t*(b.Point.X-a.Point.X),
+			Y: a.Point.Y + t*(b.Point.Y-a.Point.Y),
+			Z: -near,
+		},
+		Color: lerpColor(a.Color, b.Color, t),
+	}
+}
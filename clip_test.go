@@ -0,0 +1,89 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestClipNearAllInsideReturnsUnchanged(t *testing.T) {
+	tri := [3]ShadedVertex{
+		{Point: Point{X: 0, Y: 0, Z: -2}, Color: color.White},
+		{Point: Point{X: 1, Y: 0, Z: -2}, Color: color.White},
+		{Point: Point{X: 0, Y: 1, Z: -2}, Color: color.White},
+	}
+	got := clipNear(tri, 1)
+	if len(got) != 1 || got[0] != tri {
+		t.Errorf("clipNear with all vertices in front of the near plane = %v, want [%v]", got, tri)
+	}
+}
+
+func TestClipNearAllOutsideReturnsNothing(t *testing.T) {
+	tri := [3]ShadedVertex{
+		{Point: Point{X: 0, Y: 0, Z: 2}},
+		{Point: Point{X: 1, Y: 0, Z: 2}},
+		{Point: Point{X: 0, Y: 1, Z: 2}},
+	}
+	if got := clipNear(tri, 1); got != nil {
+		t.Errorf("clipNear with all vertices behind the near plane = %v, want nil", got)
+	}
+}
+
+func TestClipNearOneInsideProducesOneTriangle(t *testing.T) {
+	tri := [3]ShadedVertex{
+		{Point: Point{X: 0, Y: 0, Z: -2}, Color: color.White}, // inside
+		{Point: Point{X: 2, Y: 0, Z: 0}, Color: color.Black},  // outside
+		{Point: Point{X: 0, Y: 2, Z: 2}, Color: color.Black},  // outside
+	}
+	got := clipNear(tri, 1)
+	if len(got) != 1 {
+		t.Fatalf("clipNear with 2 vertices behind the plane = %d triangles, want 1", len(got))
+	}
+	for _, v := range got[0] {
+		if v.Point.Z > -1+1e-9 {
+			t.Errorf("clipped triangle vertex %v lies behind the near plane", v)
+		}
+	}
+}
+
+func TestClipNearTwoInsideProducesTwoTriangles(t *testing.T) {
+	tri := [3]ShadedVertex{
+		{Point: Point{X: 0, Y: 0, Z: -2}, Color: color.White},
+		{Point: Point{X: 1, Y: 0, Z: -2}, Color: color.White},
+		{Point: Point{X: 0, Y: 1, Z: 2}, Color: color.Black},
+	}
+	got := clipNear(tri, 1)
+	if len(got) != 2 {
+		t.Fatalf("clipNear with 1 vertex behind the plane = %d triangles, want 2", len(got))
+	}
+	for _, out := range got {
+		for _, v := range out {
+			if v.Point.Z > -1+1e-9 {
+				t.Errorf("clipped triangle vertex %v lies behind the near plane", v)
+			}
+		}
+	}
+}
+
+func TestCullModeCulled(t *testing.T) {
+	towardCamera := Vector{X: 0, Y: 0, Z: 1}
+	awayFromCamera := Vector{X: 0, Y: 0, Z: -1}
+
+	cases := []struct {
+		name   string
+		mode   CullMode
+		normal Vector
+		want   bool
+	}{
+		{"none never culls front-facing", CullNone, towardCamera, false},
+		{"none never culls back-facing", CullNone, awayFromCamera, false},
+		{"back culls back-facing", CullBack, awayFromCamera, true},
+		{"back keeps front-facing", CullBack, towardCamera, false},
+		{"front culls front-facing", CullFront, towardCamera, true},
+		{"front keeps back-facing", CullFront, awayFromCamera, false},
+	}
+	for _, tc := range cases {
+		if got := tc.mode.culled(tc.normal); got != tc.want {
+			t.Errorf("%s: culled(%v) = %v, want %v", tc.name, tc.normal, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Controller drives a Camera from WASD keys and mouse-look each frame,
+// in place of the fixed viewpoint the demo otherwise uses.
+type Controller struct {
+	Camera *Camera
+
+	Yaw, Pitch float64
+	MoveSpeed  float64
+	LookSpeed  float64
+	InvertY    bool
+
+	lastMouse pixel.Vec
+	hasMouse  bool
+}
+
+func NewController(camera *Camera) *Controller {
+	return &Controller{
+		Camera:    camera,
+		MoveSpeed: 100,
+		LookSpeed: 0.0025,
+	}
+}
+
+// Update reads win's current input state and moves/turns the camera
+// accordingly. dt is the elapsed time since the last frame, in seconds.
+func (c *Controller) Update(win *pixelgl.Window, dt float64) {
+	mouse := win.MousePosition()
+	if c.hasMouse {
+		delta := mouse.Sub(c.lastMouse)
+		invert := 1.0
+		if c.InvertY {
+			invert = -1
+		}
+		c.Yaw += delta.X * c.LookSpeed
+		c.Pitch += delta.Y * c.LookSpeed * invert
+		c.Pitch = clampFloat(c.Pitch, -math.Pi/2+0.01, math.Pi/2-0.01)
+	}
+	c.lastMouse = mouse
+	c.hasMouse = true
+
+	forward := normalize(Vector{
+		X: math.Cos(c.Pitch) * math.Sin(c.Yaw),
+		Y: math.Sin(c.Pitch),
+		Z: -math.Cos(c.Pitch) * math.Cos(c.Yaw),
+	})
+	right := normalize(cross(forward, Vector{X: 0, Y: 1, Z: 0}))
+	up := cross(right, forward)
+
+	move := c.MoveSpeed * dt
+	if win.Pressed(pixelgl.KeyW) {
+		c.Camera.Position = add(c.Camera.Position, scale(forward, move))
+	}
+	if win.Pressed(pixelgl.KeyS) {
+		c.Camera.Position = add(c.Camera.Position, scale(forward, -move))
+	}
+	if win.Pressed(pixelgl.KeyD) {
+		c.Camera.Position = add(c.Camera.Position, scale(right, move))
+	}
+	if win.Pressed(pixelgl.KeyA) {
+		c.Camera.Position = add(c.Camera.Position, scale(right, -move))
+	}
+	if win.Pressed(pixelgl.KeySpace) {
+		c.Camera.Position = add(c.Camera.Position, scale(up, move))
+	}
+	if win.Pressed(pixelgl.KeyLeftShift) {
+		c.Camera.Position = add(c.Camera.Position, scale(up, -move))
+	}
+
+	c.Camera.Target = add(c.Camera.Position, forward)
+	c.Camera.Up = Vector{X: 0, Y: 1, Z: 0}
+
+	if scroll := win.MouseScroll(); scroll.Y != 0 {
+		c.Camera.Fov = clampFloat(c.Camera.Fov-scroll.Y*0.05, 0.1, math.Pi-0.1)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// ShadingMode selects how Space.Draw colors rasterized triangles.
+type ShadingMode int
+
+const (
+	Flat ShadingMode = iota
+	Gouraud
+	Wireframe
+)
+
+// Light is implemented by anything Space.Lights can hold. Shade returns
+// the light's additive contribution, as a per-channel multiplier, to a
+// surface with the given normal.
+type Light interface {
+	Shade(normal Vector) (r, g, b float64)
+}
+
+// DirectionalLight shines uniformly from Dir, like sunlight.
+type DirectionalLight struct {
+	Dir       Vector
+	Color     color.Color
+	Intensity float64
+}
+
+func (l DirectionalLight) Shade(normal Vector) (r, g, b float64) {
+	toLight := Vector{X: -l.Dir.X, Y: -l.Dir.Y, Z: -l.Dir.Z}
+	diffuse := math.Max(0, dot(normalize(normal), normalize(toLight)))
+	cr, cg, cb, _ := l.Color.RGBA()
+	scale := diffuse * l.Intensity / 0xffff
+	return float64(cr) * scale, float64(cg) * scale, float64(cb) * scale
+}
+
+// AmbientLight contributes the same amount everywhere, regardless of
+// surface orientation.
+type AmbientLight struct {
+	Color     color.Color
+	Intensity float64
+}
+
+func (l AmbientLight) Shade(normal Vector) (r, g, b float64) {
+	cr, cg, cb, _ := l.Color.RGBA()
+	scale := l.Intensity / 0xffff
+	return float64(cr) * scale, float64(cg) * scale, float64(cb) * scale
+}
+
+// shade modulates base by the summed contribution of every light, for a
+// surface with the given normal.
+func shade(base color.Color, lights []Light, normal Vector) color.Color {
+	var r, g, b float64
+	for _, light := range lights {
+		lr, lg, lb := light.Shade(normal)
+		r += lr
+		g += lg
+		b += lb
+	}
+
+	nc := toNRGBA(base)
+	return color.NRGBA{
+		R: clampByte(float64(nc.R) * r),
+		G: clampByte(float64(nc.G) * g),
+		B: clampByte(float64(nc.B) * b),
+		A: nc.A,
+	}
+}
+
+// vertexNormals returns the polygon's per-vertex normals, auto-computing
+// a single flat face normal for every vertex when Normals hasn't been
+// set explicitly.
+func (p *Polygon) vertexNormals() []Vector {
+	if len(p.Normals) == len(p.Points) {
+		return p.Normals
+	}
+	n := normalize(triangleNormal(p.Points[0], p.Points[1], p.Points[2]))
+	normals := make([]Vector, len(p.Points))
+	for i := range normals {
+		normals[i] = n
+	}
+	return normals
+}
+
+// transformNormal rotates a normal by m, dropping any translation, and
+// re-normalizes the result.
+func transformNormal(m Mat4, n Vector) Vector {
+	v := m.MulVec4([4]float64{n.X, n.Y, n.Z, 0})
+	return normalize(Vector{X: v[0], Y: v[1], Z: v[2]})
+}
@@ -2,8 +2,8 @@ package main
 
 import (
 	"image/color"
+	"log"
 	"math"
-	"sort"
 	"time"
 
 	"github.com/faiface/pixel"
@@ -19,8 +19,9 @@ type Point struct {
 }
 
 type Polygon struct {
-	Points []Point
-	Color  color.Color
+	Points  []Point
+	Normals []Vector // optional per-vertex normals; derived from the face normal when nil
+	Color   color.Color
 }
 
 type Pyramid struct {
@@ -63,23 +64,6 @@ func (p *Polygon) Transform(m Matrix) *Polygon {
 	}
 }
 
-func computeCentroid(points []Point) Point {
-	var sumX, sumY, sumZ float64
-	n := float64(len(points))
-
-	for _, p := range points {
-		sumX += p.X
-		sumY += p.Y
-		sumZ += p.Z
-	}
-
-	return Point{
-		X: sumX / n,
-		Y: sumY / n,
-		Z: sumZ / n,
-	}
-}
-
 func NewPolygon(points []Point, color color.Color) *Polygon {
 	if len(points) < 3 {
 		panic("A polygon must have at least 3 points")
@@ -90,33 +74,6 @@ func NewPolygon(points []Point, color color.Color) *Polygon {
 	}
 }
 
-func euclideanDistance(a, b Point) float64 {
-	return math.Sqrt(math.Pow(b.X-a.X, 2) + math.Pow(b.Y-a.Y, 2) + math.Pow(b.Z-a.Z, 2))
-}
-
-type PointsSet []Point
-
-type ByCentroidDistance struct {
-	ps       []PointsSet
-	refPoint Point
-}
-
-func (bd ByCentroidDistance) Len() int {
-	return len(bd.ps)
-}
-
-func (bd ByCentroidDistance) Swap(i, j int) {
-	bd.ps[i], bd.ps[j] = bd.ps[j], bd.ps[i]
-}
-
-func (bd ByCentroidDistance) Less(i, j int) bool {
-
-	centroidI := computeCentroid(bd.ps[i])
-	centroidJ := computeCentroid(bd.ps[j])
-
-	return euclideanDistance(centroidI, bd.refPoint) > euclideanDistance(centroidJ, bd.refPoint)
-}
-
 func NewPyramid(p1, p2, p3, p4 Vector) []Polygon {
 	return []Polygon{
 		*NewPolygon([]Point{{X: p1.X, Y: p1.Y, Z: p1.Z}, {X: p2.X, Y: p2.Y, Z: p2.Z}, {X: p3.X, Y: p3.Y, Z: p3.Z}}, colornames.Red),
@@ -127,8 +84,14 @@ func NewPyramid(p1, p2, p3, p4 Vector) []Polygon {
 }
 
 type Space struct {
-	Matrix  Matrix
-	Objects []*Polygon
+	Matrix      Matrix
+	Camera      *Camera
+	Objects     []*Polygon
+	Lights      []Light
+	ShadingMode ShadingMode
+	CullMode    CullMode
+
+	framebuffer *Framebuffer
 }
 
 func NewSpace() *Space {
@@ -138,29 +101,22 @@ func NewSpace() *Space {
 			Y: Vector{X: 0, Y: 1, Z: 0},
 			Z: Vector{X: 0, Y: 0, Z: 1},
 		},
+		Camera: NewCamera(
+			Vector{X: 0, Y: 0, Z: 200},
+			Vector{X: 0, Y: 0, Z: 0},
+			Vector{X: 0, Y: 1, Z: 0},
+			math.Pi/4, 1, 1000, 1,
+		),
 		Objects: nil,
 	}
 }
 
+// Rotate composes rotationMatrix onto s.Matrix (s.Matrix = s.Matrix *
+// rotationMatrix), via Mat4.Mul rather than a hand-rolled 3x3 multiply.
+// ToMat4 embeds each 3x3 transposed, so the same product run through
+// Mat4.Mul comes out transposed too; MatrixFromMat4 undoes that.
 func (s *Space) Rotate(rotationMatrix Matrix) {
-	transformedMatrix := Matrix{
-		X: Vector{
-			X: s.Matrix.X.X*rotationMatrix.X.X + s.Matrix.X.Y*rotationMatrix.Y.X + s.Matrix.X.Z*rotationMatrix.Z.X,
-			Y: s.Matrix.X.X*rotationMatrix.X.Y + s.Matrix.X.Y*rotationMatrix.Y.Y + s.Matrix.X.Z*rotationMatrix.Z.Y,
-			Z: s.Matrix.X.X*rotationMatrix.X.Z + s.Matrix.X.Y*rotationMatrix.Y.Z + s.Matrix.X.Z*rotationMatrix.Z.Z,
-		},
-		Y: Vector{
-			X: s.Matrix.Y.X*rotationMatrix.X.X + s.Matrix.Y.Y*rotationMatrix.Y.X + s.Matrix.Y.Z*rotationMatrix.Z.X,
-			Y: s.Matrix.Y.X*rotationMatrix.X.Y + s.Matrix.Y.Y*rotationMatrix.Y.Y + s.Matrix.Y.Z*rotationMatrix.Z.Y,
-			Z: s.Matrix.Y.X*rotationMatrix.X.Z + s.Matrix.Y.Y*rotationMatrix.Y.Z + s.Matrix.Y.Z*rotationMatrix.Z.Z,
-		},
-		Z: Vector{
-			X: s.Matrix.Z.X*rotationMatrix.X.X + s.Matrix.Z.Y*rotationMatrix.Y.X + s.Matrix.Z.Z*rotationMatrix.Z.X,
-			Y: s.Matrix.Z.X*rotationMatrix.X.Y + s.Matrix.Z.Y*rotationMatrix.Y.Y + s.Matrix.Z.Z*rotationMatrix.Z.Y,
-			Z: s.Matrix.Z.X*rotationMatrix.X.Z + s.Matrix.Z.Y*rotationMatrix.Y.Z + s.Matrix.Z.Z*rotationMatrix.Z.Z,
-		},
-	}
-	s.Matrix = transformedMatrix
+	s.Matrix = MatrixFromMat4(rotationMatrix.ToMat4().Mul(s.Matrix.ToMat4()))
 }
 
 func (s *Space) RotateX(angle float64) {
@@ -195,31 +151,149 @@ func (s *Space) AddObject(p *Polygon) {
 	s.Objects = append(s.Objects, p)
 }
 
-func matrixMultiply(m Matrix, p Point) Point {
-	return Point{
-		X: p.X*m.X.X + p.Y*m.Y.X + p.Z*m.Z.X,
-		Y: p.X*m.X.Y + p.Y*m.Y.Y + p.Z*m.Z.Y,
-		Z: p.X*m.X.Z + p.Y*m.Y.Z + p.Z*m.Z.Z,
+// Draw transforms every triangle into camera space, discards backfaces
+// and clips against the near plane, then shades and rasterizes what is
+// left into a per-pixel Z-buffer (or, in Wireframe mode, just draws the
+// clipped edges with imd). This replaces the old painter's-algorithm
+// centroid sort, which falls apart on interpenetrating or long/thin
+// triangles, and keeps geometry that crosses the camera from exploding
+// through the perspective divide.
+func (s *Space) Draw(imd *imdraw.IMDraw, win *pixelgl.Window) {
+	modelMat := s.Matrix.ToMat4()
+	modelView := s.Camera.View().Mul(modelMat)
+	projection := s.Camera.Projection()
+	bounds := win.Bounds()
+	width, height := int(bounds.W()), int(bounds.H())
+
+	if s.ShadingMode != Wireframe {
+		if s.framebuffer == nil || s.framebuffer.Width != width || s.framebuffer.Height != height {
+			s.framebuffer = NewFramebuffer(width, height)
+		}
+		s.framebuffer.Clear(colornames.Dimgray)
+	}
+
+	var overlays []*Polygon
+	for _, obj := range s.Objects {
+		if len(obj.Points) != 3 {
+			continue
+		}
+
+		var cameraPoints [3]Point
+		for i, p := range obj.Points {
+			v := modelView.MulVec4([4]float64{p.X, p.Y, p.Z, 1})
+			cameraPoints[i] = Point{X: v[0], Y: v[1], Z: v[2]}
+		}
+
+		// Collapsed "line" objects (e.g. the axis markers in run(), built
+		// with their first two points duplicated) have zero area and no
+		// well-defined face normal; never cull them and never hand them
+		// to the rasterizer, which can't fill a zero-area triangle.
+		faceNormalCamera := normalize(triangleNormal(cameraPoints[0], cameraPoints[1], cameraPoints[2]))
+		degenerate := faceNormalCamera == (Vector{})
+		if !degenerate && s.CullMode.culled(faceNormalCamera) {
+			continue
+		}
+
+		// Lighting is computed in world space (model transform only, no
+		// view), matching DirectionalLight.Dir's fixed world-space
+		// frame. Shading normals in camera space would make the lit side of
+		// every object swim as the user's free-look camera rotates.
+		var tri [3]ShadedVertex
+		switch {
+		case degenerate:
+			for i := range tri {
+				tri[i] = ShadedVertex{Point: cameraPoints[i], Color: obj.Color}
+			}
+		case s.ShadingMode == Gouraud:
+			normals := obj.vertexNormals()
+			for i := range tri {
+				n := transformNormal(modelMat, normals[i])
+				tri[i] = ShadedVertex{Point: cameraPoints[i], Color: shade(obj.Color, s.Lights, n)}
+			}
+		case s.ShadingMode == Wireframe:
+			for i := range tri {
+				tri[i] = ShadedVertex{Point: cameraPoints[i], Color: obj.Color}
+			}
+		default: // Flat
+			faceNormalWorld := transformNormal(modelMat, triangleNormal(obj.Points[0], obj.Points[1], obj.Points[2]))
+			c := shade(obj.Color, s.Lights, faceNormalWorld)
+			for i := range tri {
+				tri[i] = ShadedVertex{Point: cameraPoints[i], Color: c}
+			}
+		}
+
+		for _, clipped := range clipNear(tri, s.Camera.Near) {
+			screen := projectVertices(clipped, projection, bounds)
+			if degenerate || s.ShadingMode == Wireframe {
+				// Deferred past the sprite blit below: build() draws
+				// straight onto win, and the rasterized frame is blitted
+				// as a single sprite covering the whole window, so any
+				// overlay drawn before that blit would just be painted over.
+				overlays = append(overlays, &Polygon{
+					Points: []Point{screen[0].Point, screen[1].Point, screen[2].Point},
+					Color:  obj.Color,
+				})
+				continue
+			}
+			local := toFramebufferSpace(screen, bounds)
+			s.framebuffer.DrawTriangle(local[0], local[1], local[2])
+		}
+	}
+
+	if s.ShadingMode != Wireframe {
+		sprite := pixel.NewSprite(s.framebuffer.Picture(), pixel.R(0, 0, float64(width), float64(height)))
+		sprite.Draw(win, pixel.IM.Moved(bounds.Center()))
+	}
+
+	for _, overlay := range overlays {
+		overlay.build(imd, win)
 	}
 }
 
-func (s *Space) Draw(imd *imdraw.IMDraw, win *pixelgl.Window) {
-	referencePoint := Point{X: 0, Y: 0, Z: 200}
-
-	// Sort polygons by distance from the reference point
-	polygons := make([]PointsSet, len(s.Objects))
-	for i, obj := range s.Objects {
-		points := make([]Point, 3)
-		for _, point := range obj.Points {
-			points = append(points, matrixMultiply(s.Matrix, point))
+// projectVertices runs each vertex's point through clip space with m
+// (the camera's projection matrix, applied to camera-space points),
+// performs the perspective divide, and maps the resulting NDC
+// coordinates onto bounds, carrying each vertex's color through unchanged.
+func projectVertices(tri [3]ShadedVertex, m Mat4, bounds pixel.Rect) [3]ShadedVertex {
+	var out [3]ShadedVertex
+	for i, v := range tri {
+		clip := m.MulVec4([4]float64{v.Point.X, v.Point.Y, v.Point.Z, 1})
+		w := clip[3]
+		if w == 0 {
+			w = 1e-6
+		}
+		out[i] = ShadedVertex{
+			Point: Point{
+				X: bounds.Min.X + (clip[0]/w+1)/2*bounds.W(),
+				Y: bounds.Min.Y + (clip[1]/w+1)/2*bounds.H(),
+				Z: clip[2] / w,
+			},
+			Color: v.Color,
 		}
-		polygons[i] = points
 	}
-	sort.Sort(ByCentroidDistance{ps: polygons, refPoint: referencePoint})
-	for _, obj := range s.Objects {
-		newObj := obj.Transform(s.Matrix)
-		newObj.build(imd, win)
+	return out
+}
+
+// toFramebufferSpace shifts window-space screen points (as produced by
+// projectVertices, which range over bounds) into framebuffer-local
+// pixel coordinates, where (0,0) is bounds.Min rather than the window's
+// own origin. Framebuffer.DrawTriangle indexes its depth buffer with
+// these local coordinates, so skipping this step silently rasterizes
+// nothing for any triangle that falls outside the window's positive
+// quadrant.
+func toFramebufferSpace(tri [3]ShadedVertex, bounds pixel.Rect) [3]ShadedVertex {
+	var out [3]ShadedVertex
+	for i, v := range tri {
+		out[i] = ShadedVertex{
+			Point: Point{
+				X: v.Point.X - bounds.Min.X,
+				Y: v.Point.Y - bounds.Min.Y,
+				Z: v.Point.Z,
+			},
+			Color: v.Color,
+		}
 	}
+	return out
 }
 
 func run() {
@@ -234,6 +308,13 @@ func run() {
 	if err != nil {
 		panic(err)
 	}
+	space.Camera.Aspect = win.Bounds().W() / win.Bounds().H()
+	space.ShadingMode = Gouraud
+	space.CullMode = CullBack
+	space.Lights = []Light{
+		DirectionalLight{Dir: Vector{X: -0.4, Y: -1, Z: -0.3}, Color: colornames.White, Intensity: 1},
+		AmbientLight{Color: colornames.White, Intensity: 0.25},
+	}
 
 	p1 := Point{X: -50, Y: 0, Z: -50}
 	p2 := Point{X: 0, Y: 100, Z: 0}
@@ -282,11 +363,28 @@ func run() {
 		p2, p4, p5,
 	}, colornames.Aliceblue))
 
+	// A sample model, teapot.obj in the working directory, is entirely
+	// optional: the hand-coded geometry above stands on its own.
+	identity := Matrix{
+		X: Vector{X: 1, Y: 0, Z: 0},
+		Y: Vector{X: 0, Y: 1, Z: 0},
+		Z: Vector{X: 0, Y: 0, Z: 1},
+	}
+	if mesh, err := LoadOBJ("teapot.obj"); err != nil {
+		log.Printf("no sample model loaded: %v", err)
+	} else {
+		space.AddMesh(mesh, identity, colornames.Orange)
+	}
+
+	controller := NewController(space.Camera)
+
 	// space.RotateZ(-0.033)
 	space.RotateX(-0.33)
 	for !win.Closed() {
 		win.Clear(colornames.Dimgray)
 
+		controller.Update(win, FrameLength.Seconds())
+
 		imd := imdraw.New(nil) // Create a new immediate-mode drawing context
 		space.Draw(imd, win)
 		// space.RotateX(-0.033)
@@ -0,0 +1,144 @@
+package main
+
+import "math"
+
+// Mat4 is a 4x4 matrix in row-major order. Points are treated as column
+// vectors, so a transform is applied as p' = M * [x, y, z, w].
+type Mat4 [4][4]float64
+
+func Identity4() Mat4 {
+	return Mat4{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+func (a Mat4) Mul(b Mat4) Mat4 {
+	var out Mat4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			var sum float64
+			for k := 0; k < 4; k++ {
+				sum += a[i][k] * b[k][j]
+			}
+			out[i][j] = sum
+		}
+	}
+	return out
+}
+
+func (m Mat4) MulVec4(v [4]float64) [4]float64 {
+	var out [4]float64
+	for i := 0; i < 4; i++ {
+		out[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2] + m[i][3]*v[3]
+	}
+	return out
+}
+
+func Translate(v Vector) Mat4 {
+	m := Identity4()
+	m[0][3] = v.X
+	m[1][3] = v.Y
+	m[2][3] = v.Z
+	return m
+}
+
+func Scale(v Vector) Mat4 {
+	m := Identity4()
+	m[0][0] = v.X
+	m[1][1] = v.Y
+	m[2][2] = v.Z
+	return m
+}
+
+func normalize(v Vector) Vector {
+	length := math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+	if length == 0 {
+		return v
+	}
+	return Vector{X: v.X / length, Y: v.Y / length, Z: v.Z / length}
+}
+
+func cross(a, b Vector) Vector {
+	return Vector{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+}
+
+func dot(a, b Vector) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func sub(a, b Vector) Vector {
+	return Vector{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+func add(a, b Vector) Vector {
+	return Vector{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z}
+}
+
+func scale(v Vector, s float64) Vector {
+	return Vector{X: v.X * s, Y: v.Y * s, Z: v.Z * s}
+}
+
+func clampFloat(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// LookAt builds a view matrix that carries world-space points into the
+// coordinate frame of a camera at eye, looking toward target.
+func LookAt(eye, target, up Vector) Mat4 {
+	forward := normalize(sub(target, eye))
+	right := normalize(cross(forward, up))
+	trueUp := cross(right, forward)
+
+	return Mat4{
+		{right.X, right.Y, right.Z, -dot(right, eye)},
+		{trueUp.X, trueUp.Y, trueUp.Z, -dot(trueUp, eye)},
+		{-forward.X, -forward.Y, -forward.Z, dot(forward, eye)},
+		{0, 0, 0, 1},
+	}
+}
+
+// Perspective builds a right-handed perspective projection matrix, with
+// fovY the vertical field of view in radians.
+func Perspective(fovY, aspect, near, far float64) Mat4 {
+	f := 1 / math.Tan(fovY/2)
+	return Mat4{
+		{f / aspect, 0, 0, 0},
+		{0, f, 0, 0},
+		{0, 0, (far + near) / (near - far), (2 * far * near) / (near - far)},
+		{0, 0, -1, 0},
+	}
+}
+
+// ToMat4 embeds the legacy 3x3 rotation matrix into a 4x4 homogeneous
+// one, transposed so it keeps transforming points the same way the
+// row-vector math in Rotate/RotateX/Y/Z always has.
+func (m Matrix) ToMat4() Mat4 {
+	out := Identity4()
+	out[0][0], out[0][1], out[0][2] = m.X.X, m.Y.X, m.Z.X
+	out[1][0], out[1][1], out[1][2] = m.X.Y, m.Y.Y, m.Z.Y
+	out[2][0], out[2][1], out[2][2] = m.X.Z, m.Y.Z, m.Z.Z
+	return out
+}
+
+// MatrixFromMat4 extracts the upper-left 3x3 rotation back out of a
+// Mat4, undoing the transpose ToMat4 applies.
+func MatrixFromMat4(m Mat4) Matrix {
+	return Matrix{
+		X: Vector{X: m[0][0], Y: m[1][0], Z: m[2][0]},
+		Y: Vector{X: m[0][1], Y: m[1][1], Z: m[2][1]},
+		Z: Vector{X: m[0][2], Y: m[1][2], Z: m[2][2]},
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMat4MulIdentity(t *testing.T) {
+	m := Mat4{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+		{13, 14, 15, 16},
+	}
+	got := m.Mul(Identity4())
+	if got != m {
+		t.Errorf("m * I = %v, want %v", got, m)
+	}
+}
+
+func TestMat4MulVec4Translate(t *testing.T) {
+	m := Translate(Vector{X: 1, Y: 2, Z: 3})
+	got := m.MulVec4([4]float64{0, 0, 0, 1})
+	want := [4]float64{1, 2, 3, 1}
+	if got != want {
+		t.Errorf("Translate applied to origin = %v, want %v", got, want)
+	}
+}
+
+func TestScale(t *testing.T) {
+	m := Scale(Vector{X: 2, Y: 3, Z: 4})
+	got := m.MulVec4([4]float64{1, 1, 1, 1})
+	want := [4]float64{2, 3, 4, 1}
+	if got != want {
+		t.Errorf("Scale applied to (1,1,1) = %v, want %v", got, want)
+	}
+}
+
+func TestLookAtPlacesTargetOnNegativeZ(t *testing.T) {
+	view := LookAt(Vector{X: 0, Y: 0, Z: 5}, Vector{X: 0, Y: 0, Z: 0}, Vector{X: 0, Y: 1, Z: 0})
+	v := view.MulVec4([4]float64{0, 0, 0, 1})
+	if !almostEqual(v[0], 0) || !almostEqual(v[1], 0) || !almostEqual(v[2], -5) {
+		t.Errorf("target in camera space = %v, want (0,0,-5)", v)
+	}
+}
+
+func TestPerspectiveProjectsCenterToOrigin(t *testing.T) {
+	proj := Perspective(math.Pi/2, 1, 1, 100)
+	clip := proj.MulVec4([4]float64{0, 0, -10, 1})
+	if !almostEqual(clip[0], 0) || !almostEqual(clip[1], 0) {
+		t.Errorf("a point on the view axis should project to x=y=0 in clip space, got %v", clip)
+	}
+	if clip[3] <= 0 {
+		t.Errorf("clip.w = %v, want > 0 for a point in front of the camera", clip[3])
+	}
+}
+
+func TestCameraViewProjectionRoundTrip(t *testing.T) {
+	cam := NewCamera(Vector{X: 0, Y: 0, Z: 5}, Vector{X: 0, Y: 0, Z: 0}, Vector{X: 0, Y: 1, Z: 0}, math.Pi/2, 0.1, 100, 1)
+	mv := cam.View().MulVec4([4]float64{0, 0, 0, 1})
+	if !almostEqual(mv[2], -5) {
+		t.Fatalf("target in view space z = %v, want -5", mv[2])
+	}
+	clip := cam.Projection().MulVec4(mv)
+	if clip[3] <= 0 {
+		t.Errorf("clip.w = %v, want > 0", clip[3])
+	}
+	ndcX, ndcY := clip[0]/clip[3], clip[1]/clip[3]
+	if !almostEqual(ndcX, 0) || !almostEqual(ndcY, 0) {
+		t.Errorf("target NDC = (%v, %v), want (0, 0)", ndcX, ndcY)
+	}
+}
+
+func TestToMat4MatrixFromMat4RoundTrip(t *testing.T) {
+	m := Matrix{
+		X: Vector{X: 0, Y: 1, Z: 0},
+		Y: Vector{X: -1, Y: 0, Z: 0},
+		Z: Vector{X: 0, Y: 0, Z: 1},
+	}
+	got := MatrixFromMat4(m.ToMat4())
+	if got != m {
+		t.Errorf("round trip through ToMat4/MatrixFromMat4 = %v, want %v", got, m)
+	}
+}
+
+// mulMatrix3x3 multiplies two 3x3 Matrix values the same way Transform
+// applies them: out = a * b, with each Vector field a row.
+func mulMatrix3x3(a, b Matrix) Matrix {
+	rows := [3]Vector{a.X, a.Y, a.Z}
+	cols := func(v Vector) [3]float64 { return [3]float64{v.X, v.Y, v.Z} }
+	bRows := [3][3]float64{cols(b.X), cols(b.Y), cols(b.Z)}
+	var out [3]Vector
+	for i, row := range rows {
+		r := cols(row)
+		var sum [3]float64
+		for k := 0; k < 3; k++ {
+			for j := 0; j < 3; j++ {
+				sum[j] += r[k] * bRows[k][j]
+			}
+		}
+		out[i] = Vector{X: sum[0], Y: sum[1], Z: sum[2]}
+	}
+	return Matrix{X: out[0], Y: out[1], Z: out[2]}
+}
+
+func TestSpaceRotateMatchesManual3x3Multiply(t *testing.T) {
+	space := NewSpace()
+	space.Matrix = Matrix{
+		X: Vector{X: 0, Y: 1, Z: 0},
+		Y: Vector{X: -1, Y: 0, Z: 0},
+		Z: Vector{X: 0, Y: 0, Z: 1},
+	}
+	rotation := Matrix{
+		X: Vector{X: 1, Y: 0, Z: 0},
+		Y: Vector{X: 0, Y: math.Cos(0.7), Z: math.Sin(0.7)},
+		Z: Vector{X: 0, Y: -math.Sin(0.7), Z: math.Cos(0.7)},
+	}
+	want := mulMatrix3x3(space.Matrix, rotation)
+
+	space.Rotate(rotation)
+
+	if space.Matrix != want {
+		t.Errorf("Rotate result = %v, want %v", space.Matrix, want)
+	}
+}
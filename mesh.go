@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Mesh is an indexed triangle mesh: a flat vertex list plus triangles
+// referencing those vertices by index, mirroring the Mesh{Vertices, Faces}
+// shape used by other renderers.
+type Mesh struct {
+	Vertices []Vector
+	Faces    [][3]int
+}
+
+// LoadOBJ parses the v and f lines of a Wavefront OBJ file at path. It
+// supports negative (relative) indices and f v/vt/vn triplets, ignoring
+// the vt/vn components, and triangulates n-gon faces by fan
+// triangulation around their first vertex.
+func LoadOBJ(path string) (*Mesh, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mesh := &Mesh{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("malformed vertex line: %q", scanner.Text())
+			}
+			x, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, err
+			}
+			y, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, err
+			}
+			z, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return nil, err
+			}
+			mesh.Vertices = append(mesh.Vertices, Vector{X: x, Y: y, Z: z})
+
+		case "f":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("malformed face line: %q", scanner.Text())
+			}
+			indices := make([]int, len(fields)-1)
+			for i, field := range fields[1:] {
+				idx, err := parseFaceIndex(field, len(mesh.Vertices))
+				if err != nil {
+					return nil, err
+				}
+				indices[i] = idx
+			}
+			for i := 1; i < len(indices)-1; i++ {
+				mesh.Faces = append(mesh.Faces, [3]int{indices[0], indices[i], indices[i+1]})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
+
+// parseFaceIndex reads a single f-line vertex reference (v, v/vt or
+// v/vt/vn) and returns a zero-based vertex index, resolving OBJ's
+// negative (relative-to-end) indices against vertexCount.
+func parseFaceIndex(field string, vertexCount int) (int, error) {
+	vertexPart := strings.SplitN(field, "/", 2)[0]
+	idx, err := strconv.Atoi(vertexPart)
+	if err != nil {
+		return 0, fmt.Errorf("malformed face index: %q", field)
+	}
+	if idx < 0 {
+		idx = vertexCount + idx
+	} else {
+		idx--
+	}
+	if idx < 0 || idx >= vertexCount {
+		return 0, fmt.Errorf("face index %d out of range (have %d vertices)", idx, vertexCount)
+	}
+	return idx, nil
+}
+
+func transformPoint(m Matrix, p Point) Point {
+	return Point{
+		X: p.X*m.X.X + p.Y*m.Y.X + p.Z*m.Z.X,
+		Y: p.X*m.X.Y + p.Y*m.Y.Y + p.Z*m.Z.Y,
+		Z: p.X*m.X.Z + p.Y*m.Y.Z + p.Z*m.Z.Z,
+	}
+}
+
+// AddMesh expands every triangle of m into the Space's Objects slice,
+// applying transform to each vertex and painting the whole mesh with a
+// single color. This lets users view arbitrary loaded models alongside
+// the hand-coded demo geometry.
+func (s *Space) AddMesh(m *Mesh, transform Matrix, c color.Color) {
+	for _, face := range m.Faces {
+		points := make([]Point, 3)
+		for i, vertexIndex := range face {
+			v := m.Vertices[vertexIndex]
+			points[i] = transformPoint(transform, Point{X: v.X, Y: v.Y, Z: v.Z})
+		}
+		s.AddObject(NewPolygon(points, c))
+	}
+}
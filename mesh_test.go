@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeOBJ(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mesh.obj")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile(%q): %v", path, err)
+	}
+	return path
+}
+
+func TestLoadOBJTriangle(t *testing.T) {
+	path := writeOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1 2 3
+`)
+	mesh, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ: %v", err)
+	}
+	if len(mesh.Vertices) != 3 {
+		t.Fatalf("len(Vertices) = %d, want 3", len(mesh.Vertices))
+	}
+	if len(mesh.Faces) != 1 || mesh.Faces[0] != [3]int{0, 1, 2} {
+		t.Fatalf("Faces = %v, want [[0 1 2]]", mesh.Faces)
+	}
+}
+
+func TestLoadOBJFanTriangulatesQuad(t *testing.T) {
+	path := writeOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+f 1 2 3 4
+`)
+	mesh, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ: %v", err)
+	}
+	want := [][3]int{{0, 1, 2}, {0, 2, 3}}
+	if len(mesh.Faces) != len(want) {
+		t.Fatalf("Faces = %v, want %v", mesh.Faces, want)
+	}
+	for i, f := range want {
+		if mesh.Faces[i] != f {
+			t.Errorf("Faces[%d] = %v, want %v", i, mesh.Faces[i], f)
+		}
+	}
+}
+
+func TestLoadOBJNegativeIndices(t *testing.T) {
+	path := writeOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f -3 -2 -1
+`)
+	mesh, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ: %v", err)
+	}
+	if mesh.Faces[0] != [3]int{0, 1, 2} {
+		t.Errorf("Faces[0] = %v, want [0 1 2]", mesh.Faces[0])
+	}
+}
+
+func TestLoadOBJIgnoresTexcoordAndNormalIndices(t *testing.T) {
+	path := writeOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1/1/1 2/2/2 3/3/3
+`)
+	mesh, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ: %v", err)
+	}
+	if mesh.Faces[0] != [3]int{0, 1, 2} {
+		t.Errorf("Faces[0] = %v, want [0 1 2]", mesh.Faces[0])
+	}
+}
+
+func TestLoadOBJOutOfRangeIndexErrors(t *testing.T) {
+	path := writeOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+f 1 2 5
+`)
+	if _, err := LoadOBJ(path); err == nil {
+		t.Fatal("LoadOBJ with an out-of-range face index: got nil error, want non-nil")
+	}
+}
+
+func TestLoadOBJMissingFileErrors(t *testing.T) {
+	if _, err := LoadOBJ(filepath.Join(t.TempDir(), "missing.obj")); err == nil {
+		t.Fatal("LoadOBJ with a missing file: got nil error, want non-nil")
+	}
+}
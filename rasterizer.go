@@ -0,0 +1,154 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Framebuffer is a software color + depth target that Space.Draw
+// rasterizes triangles into, one pixel at a time, instead of relying on
+// draw order. It is re-created whenever the window is resized.
+type Framebuffer struct {
+	Width, Height int
+	img           *image.RGBA
+	depth         []float32
+}
+
+func NewFramebuffer(width, height int) *Framebuffer {
+	return &Framebuffer{
+		Width:  width,
+		Height: height,
+		img:    image.NewRGBA(image.Rect(0, 0, width, height)),
+		depth:  make([]float32, width*height),
+	}
+}
+
+// Clear resets every pixel to bg and every depth sample to the farthest
+// possible value, so the first triangle drawn over a pixel always wins.
+func (fb *Framebuffer) Clear(bg color.Color) {
+	draw.Draw(fb.img, fb.img.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	for i := range fb.depth {
+		fb.depth[i] = math.MaxFloat32
+	}
+}
+
+// Picture exposes the framebuffer as a pixel.Picture so it can be blit
+// onto the window as a single sprite instead of many individual draws.
+func (fb *Framebuffer) Picture() pixel.Picture {
+	return pixel.PictureDataFromImage(fb.img)
+}
+
+// set writes a pixel, flipping Y because image.RGBA is row 0 at the top
+// while window/screen coordinates from projectVertices are Y-up.
+func (fb *Framebuffer) set(x, y int, c color.Color) {
+	if x < 0 || x >= fb.Width || y < 0 || y >= fb.Height {
+		return
+	}
+	fb.img.Set(x, fb.Height-1-y, c)
+}
+
+func edgeFunction(a, b, p Point) float64 {
+	return (p.X-a.X)*(b.Y-a.Y) - (p.Y-a.Y)*(b.X-a.X)
+}
+
+// ShadedVertex is a single rasterizer input: a screen- or camera-space
+// point together with the color it carries, so triangles can be flat- or
+// Gouraud-shaded by the same barycentric interpolation.
+type ShadedVertex struct {
+	Point Point
+	Color color.Color
+}
+
+// DrawTriangle rasterizes a single screen-space triangle into the
+// framebuffer, using edge-function barycentric coordinates, a per-pixel
+// depth test against the existing contents, and per-vertex color
+// interpolation (which is a flat fill when all three vertices share a
+// color, and Gouraud shading otherwise).
+func (fb *Framebuffer) DrawTriangle(v0, v1, v2 ShadedVertex) {
+	a, b, c := v0.Point, v1.Point, v2.Point
+
+	area := edgeFunction(a, b, c)
+	if area == 0 {
+		return
+	}
+
+	minX := int(math.Floor(math.Min(a.X, math.Min(b.X, c.X))))
+	maxX := int(math.Ceil(math.Max(a.X, math.Max(b.X, c.X))))
+	minY := int(math.Floor(math.Min(a.Y, math.Min(b.Y, c.Y))))
+	maxY := int(math.Ceil(math.Max(a.Y, math.Max(b.Y, c.Y))))
+
+	minX = clampInt(minX, 0, fb.Width-1)
+	maxX = clampInt(maxX, 0, fb.Width-1)
+	minY = clampInt(minY, 0, fb.Height-1)
+	maxY = clampInt(maxY, 0, fb.Height-1)
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			sample := Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+
+			w0 := edgeFunction(b, c, sample)
+			w1 := edgeFunction(c, a, sample)
+			w2 := edgeFunction(a, b, sample)
+
+			inside := (w0 >= 0 && w1 >= 0 && w2 >= 0) || (w0 <= 0 && w1 <= 0 && w2 <= 0)
+			if !inside {
+				continue
+			}
+
+			w0 /= area
+			w1 /= area
+			w2 /= area
+			z := w0*a.Z + w1*b.Z + w2*c.Z
+
+			idx := y*fb.Width + x
+			if float32(z) < fb.depth[idx] {
+				fb.depth[idx] = float32(z)
+				fb.set(x, y, interpolateColor(v0.Color, v1.Color, v2.Color, w0, w1, w2))
+			}
+		}
+	}
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func toNRGBA(c color.Color) color.NRGBA {
+	return color.NRGBAModel.Convert(c).(color.NRGBA)
+}
+
+func clampByte(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// interpolateColor blends three vertex colors by barycentric weights.
+func interpolateColor(c0, c1, c2 color.Color, w0, w1, w2 float64) color.Color {
+	n0, n1, n2 := toNRGBA(c0), toNRGBA(c1), toNRGBA(c2)
+	return color.NRGBA{
+		R: clampByte(w0*float64(n0.R) + w1*float64(n1.R) + w2*float64(n2.R)),
+		G: clampByte(w0*float64(n0.G) + w1*float64(n1.G) + w2*float64(n2.G)),
+		B: clampByte(w0*float64(n0.B) + w1*float64(n1.B) + w2*float64(n2.B)),
+		A: clampByte(w0*float64(n0.A) + w1*float64(n1.A) + w2*float64(n2.A)),
+	}
+}
+
+// lerpColor blends two vertex colors by t in [0,1].
+func lerpColor(c0, c1 color.Color, t float64) color.Color {
+	return interpolateColor(c0, c1, c0, 1-t, t, 0)
+}